@@ -0,0 +1,176 @@
+package ffmpeg
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+)
+
+// Client holds per-instance bin paths, default options and a logger, so
+// callers can run independent ffmpeg configurations concurrently.
+type Client struct {
+	// FFMPEGBinPath overrides the package-level ffmpeg binary path.
+	// FFProbeBinPath is stored but not used by any method: ffprobe calls,
+	// such as the duration probe for progress reporting, always use the
+	// package-level ffprobe binary path.
+	FFMPEGBinPath  string
+	FFProbeBinPath string
+
+	// Logger, if set, receives ffmpeg's stderr for every command this Client runs.
+	Logger io.Writer
+
+	// DefaultExtractingImagesOption and DefaultMP4ConvertOption are used when
+	// ExtractingImagesContext/ConvertToMP4Context is called with a nil opt.
+	DefaultExtractingImagesOption *ExtractingImagesOption
+	DefaultMP4ConvertOption       *MP4ConvertOption
+}
+
+// NewClient returns a Client that defaults to the package-level binary paths.
+func NewClient() *Client {
+	return &Client{}
+}
+
+// WithLogger sets the Client's Logger and returns c for chaining.
+func (c *Client) WithLogger(w io.Writer) *Client {
+	c.Logger = w
+	return c
+}
+
+func (c *Client) ffmpegBin() string {
+	if c.FFMPEGBinPath != "" {
+		return c.FFMPEGBinPath
+	}
+	return ffmpegBinPath
+}
+
+func (c *Client) ffprobeBin() string {
+	if c.FFProbeBinPath != "" {
+		return c.FFProbeBinPath
+	}
+	return ffprobeBinPath
+}
+
+// Run executes ffmpeg with args and waits for it to finish or ctx to be
+// cancelled. If fn is non-nil, progress is reported against filePath's
+// duration. If ffmpeg runs and exits with an error, that error is returned
+// as *FFmpegError. A missing binary yields ErrFFMPEGNotFound, other start
+// failures are returned as they are, and a cancelled ctx kills the process
+// and yields ErrTimeout.
+func (c *Client) Run(ctx context.Context, args []string, filePath string, fn ProgressFunc) error {
+	if fn != nil {
+		args = append(args, "-progress", "pipe:2", "-nostats")
+	}
+
+	cmd := exec.Command(c.ffmpegBin(), args...)
+
+	var outputBuf bytes.Buffer
+	cmd.Stdout = &outputBuf
+
+	stderrCapture := newStderrRingBuffer(maxCapturedStderr)
+	var stderrSink io.Writer = stderrCapture
+	if c.Logger != nil {
+		stderrSink = io.MultiWriter(stderrCapture, c.Logger)
+	}
+
+	progressPipe, err := attachProgress(cmd, fn)
+	if err != nil {
+		return err
+	}
+	if progressPipe == nil {
+		cmd.Stderr = stderrSink
+	}
+
+	err = cmd.Start()
+	if errors.Is(err, exec.ErrNotFound) {
+		return ErrFFMPEGNotFound
+	} else if err != nil {
+		return err
+	}
+	if progressPipe != nil {
+		go watchProgress(progressPipe, probeDurationSeconds(ctx, filePath), fn, stderrSink)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- cmd.Wait()
+	}()
+
+	select {
+	case <-ctx.Done():
+		if err := cmd.Process.Kill(); err != nil {
+			return err
+		}
+		return ErrTimeout
+	case err = <-done:
+		if err != nil {
+			return &FFmpegError{
+				Err:      err,
+				Args:     args,
+				ExitCode: cmd.ProcessState.ExitCode(),
+				Stderr:   stderrCapture.String(),
+			}
+		}
+		return nil
+	}
+}
+
+// ExtractingImagesContext is used for retrieve the first frame of given
+// media file using ffmpeg, using opt (or c.DefaultExtractingImagesOption
+// if opt is nil) instead of a shared global.
+func (c *Client) ExtractingImagesContext(ctx context.Context, opt *ExtractingImagesOption) error {
+	if opt == nil {
+		opt = c.DefaultExtractingImagesOption
+	}
+	if opt == nil {
+		return fmt.Errorf("option not set")
+	}
+
+	outputFileFormat := resolveOutputFrameFileFormat(opt.FilePath)
+	resize := opt.OutputWidth != nil && opt.OutputHeight != nil
+
+	var args []string
+	if resize {
+		args = []string{
+			"-i", opt.FilePath,
+			"-r", opt.FrameRate,
+			"-s", fmt.Sprintf("%dx%d", *opt.OutputWidth, *opt.OutputHeight),
+			"-f", "image2",
+			outputFileFormat,
+		}
+	} else {
+		args = []string{
+			"-i", opt.FilePath,
+			"-r", opt.FrameRate,
+			"-f", "image2",
+			outputFileFormat,
+		}
+	}
+
+	return c.Run(ctx, args, opt.FilePath, opt.ProgressFunc)
+}
+
+// ConvertToMP4Context is used to convert a video with another format to
+// mp4 using ffmpeg, using opt (or c.DefaultMP4ConvertOption if opt is
+// nil) instead of a shared global.
+func (c *Client) ConvertToMP4Context(ctx context.Context, opt *MP4ConvertOption) error {
+	if opt == nil {
+		opt = c.DefaultMP4ConvertOption
+	}
+	if opt == nil {
+		return fmt.Errorf("option not set")
+	}
+
+	outputFileFormat := resolveOutputMP4FileFormat(opt.FilePath)
+	args := []string{
+		"-i", opt.FilePath,
+		outputFileFormat,
+	}
+	if opt.Overwrite {
+		args = append(args, "-y")
+	}
+
+	return c.Run(ctx, args, opt.FilePath, opt.ProgressFunc)
+}
@@ -0,0 +1,28 @@
+package ffmpeg
+
+// ProbeData is the parsed result of "ffprobe -show_format -show_streams",
+// as produced by GetProbeDataContext/GetProbeDataOptions.
+type ProbeData struct {
+	Format  *FormatInfo  `json:"format,omitempty"`
+	Streams []StreamInfo `json:"streams,omitempty"`
+}
+
+// FormatInfo is ffprobe's "format" object: container-level metadata.
+type FormatInfo struct {
+	Filename   string            `json:"filename"`
+	FormatName string            `json:"format_name"`
+	Duration   string            `json:"duration"`
+	Size       string            `json:"size"`
+	BitRate    string            `json:"bit_rate"`
+	Tags       map[string]string `json:"tags,omitempty"`
+}
+
+// StreamInfo is one entry of ffprobe's "streams" array: a single
+// audio/video/subtitle/data stream within the container.
+type StreamInfo struct {
+	Index     int               `json:"index"`
+	CodecName string            `json:"codec_name"`
+	CodecType string            `json:"codec_type"`
+	CodecTag  string            `json:"codec_tag_string"`
+	Tags      map[string]string `json:"tags,omitempty"`
+}
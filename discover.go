@@ -0,0 +1,162 @@
+package ffmpeg
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrFFProbeNotFound is declared in ffprobe.go.
+
+	// ErrIncompatibleVersion is returned when a discovered ffmpeg/ffprobe
+	// binary runs but reports a version older than MinimumVersion.
+	ErrIncompatibleVersion = errors.New("ffmpeg/ffprobe version is incompatible")
+
+	// MinimumVersion is the lowest ffmpeg/ffprobe version DiscoverBinaries
+	// will accept. It is a package variable rather than a constant so
+	// callers can relax or tighten it before calling DiscoverBinaries.
+	MinimumVersion = "3.0.0"
+
+	ffmpegVersion  string
+	ffprobeVersion string
+
+	versionRegexp = regexp.MustCompile(`version\s+(\d+(?:\.\d+){1,2})`)
+)
+
+// DiscoverBinaries locates and validates the ffmpeg and ffprobe binaries,
+// caching their resolved paths and versions on success.
+func DiscoverBinaries() error {
+	ffmpegPath, err := locateBinary(ffmpegBinPath)
+	if err != nil {
+		return ErrFFMPEGNotFound
+	}
+	ffmpegBinPath = ffmpegPath
+
+	version, err := runVersion(ffmpegBinPath)
+	if err != nil {
+		return fmt.Errorf("ffmpeg: %w", err)
+	}
+	if !versionAtLeast(version, MinimumVersion) {
+		return fmt.Errorf("ffmpeg %s: %w", version, ErrIncompatibleVersion)
+	}
+	ffmpegVersion = version
+
+	ffprobePath, err := locateBinary(ffprobeBinPath)
+	if err != nil {
+		return ErrFFProbeNotFound
+	}
+	ffprobeBinPath = ffprobePath
+
+	version, err = runVersion(ffprobeBinPath)
+	if err != nil {
+		return fmt.Errorf("ffprobe: %w", err)
+	}
+	if !versionAtLeast(version, MinimumVersion) {
+		return fmt.Errorf("ffprobe %s: %w", version, ErrIncompatibleVersion)
+	}
+	ffprobeVersion = version
+
+	return nil
+}
+
+// FFmpegVersion returns the cached ffmpeg version string, discovering the
+// binary first if it has not been resolved yet.
+func FFmpegVersion() (string, error) {
+	if ffmpegVersion == "" {
+		if err := DiscoverBinaries(); err != nil {
+			return "", err
+		}
+	}
+	return ffmpegVersion, nil
+}
+
+// FFProbeVersion returns the cached ffprobe version string, discovering
+// the binary first if it has not been resolved yet.
+func FFProbeVersion() (string, error) {
+	if ffprobeVersion == "" {
+		if err := DiscoverBinaries(); err != nil {
+			return "", err
+		}
+	}
+	return ffprobeVersion, nil
+}
+
+// locateBinary resolves configured to an executable path via
+// exec.LookPath, falling back to its basename in the cwd and exe dir.
+func locateBinary(configured string) (string, error) {
+	if path, err := exec.LookPath(configured); err == nil {
+		return path, nil
+	}
+
+	base := filepath.Base(configured)
+	var candidates []string
+	if wd, err := os.Getwd(); err == nil {
+		candidates = append(candidates, filepath.Join(wd, base))
+	}
+	if exe, err := os.Executable(); err == nil {
+		candidates = append(candidates, filepath.Join(filepath.Dir(exe), base))
+	}
+
+	for _, candidate := range candidates {
+		if path, err := exec.LookPath(candidate); err == nil {
+			return path, nil
+		}
+	}
+
+	return "", exec.ErrNotFound
+}
+
+// runVersion executes "bin -version" and extracts the leading version
+// number from its output, e.g. "ffmpeg version 6.1.1 Copyright...".
+func runVersion(bin string) (string, error) {
+	out, err := exec.Command(bin, "-version").Output()
+	if err != nil {
+		return "", err
+	}
+
+	matches := versionRegexp.FindSubmatch(out)
+	if matches == nil {
+		return "", fmt.Errorf("could not parse version from: %s", firstLine(out))
+	}
+	return string(matches[1]), nil
+}
+
+func firstLine(b []byte) string {
+	if i := strings.IndexByte(string(b), '\n'); i >= 0 {
+		return string(b[:i])
+	}
+	return string(b)
+}
+
+// versionAtLeast reports whether version is >= minimum, comparing
+// dot-separated numeric components left to right. An unparsable
+// component fails the comparison rather than being treated as 0.
+func versionAtLeast(version, minimum string) bool {
+	v := strings.Split(version, ".")
+	m := strings.Split(minimum, ".")
+
+	for i := 0; i < len(v) || i < len(m); i++ {
+		var vn, mn int
+		var err error
+		if i < len(v) {
+			if vn, err = strconv.Atoi(v[i]); err != nil {
+				return false
+			}
+		}
+		if i < len(m) {
+			if mn, err = strconv.Atoi(m[i]); err != nil {
+				return false
+			}
+		}
+		if vn != mn {
+			return vn > mn
+		}
+	}
+	return true
+}
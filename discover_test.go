@@ -0,0 +1,49 @@
+package ffmpeg
+
+import "testing"
+
+func TestVersionAtLeast(t *testing.T) {
+	tests := []struct {
+		version string
+		minimum string
+		want    bool
+	}{
+		{"3.0.0", "3.0.0", true},
+		{"4.2.1", "3.0.0", true},
+		{"2.9.9", "3.0.0", false},
+		{"3.1", "3.0.0", true},
+		{"3", "3.0.0", true},
+		{"3.0.0", "3.0.1", false},
+		{"abc", "3.0.0", false},
+		{"3.0.0", "abc", false},
+		{"", "3.0.0", false},
+	}
+
+	for _, tt := range tests {
+		if got := versionAtLeast(tt.version, tt.minimum); got != tt.want {
+			t.Errorf("versionAtLeast(%q, %q) = %v, want %v", tt.version, tt.minimum, got, tt.want)
+		}
+	}
+}
+
+func TestVersionRegexpExtractsLeadingVersion(t *testing.T) {
+	tests := []struct {
+		output string
+		want   string
+	}{
+		{"ffmpeg version 6.1.1 Copyright (c) 2000-2023 the FFmpeg developers", "6.1.1"},
+		{"ffprobe version 4.2.7-0ubuntu0.1 Copyright (c) 2007-2019 the FFmpeg developers", "4.2.7"},
+		{"ffmpeg version n5.0-dev", ""},
+	}
+
+	for _, tt := range tests {
+		matches := versionRegexp.FindStringSubmatch(tt.output)
+		var got string
+		if matches != nil {
+			got = matches[1]
+		}
+		if got != tt.want {
+			t.Errorf("versionRegexp.FindStringSubmatch(%q) = %q, want %q", tt.output, got, tt.want)
+		}
+	}
+}
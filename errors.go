@@ -0,0 +1,81 @@
+package ffmpeg
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// maxCapturedStderr bounds how much of a failed command's stderr an
+// FFmpegError retains.
+const maxCapturedStderr = 64 * 1024 // 64KB
+
+// FFmpegError wraps a failed ffmpeg invocation with its argv, exit code,
+// and the tail of stderr ffmpeg printed before dying.
+type FFmpegError struct {
+	Err      error
+	Args     []string
+	ExitCode int
+	Stderr   string
+}
+
+func (e *FFmpegError) Error() string {
+	return fmt.Sprintf("ffmpeg %s: %s (exit %d): %s", strings.Join(e.Args, " "), e.Err, e.ExitCode, e.Stderr)
+}
+
+// Unwrap allows errors.Is/errors.As to see through to the underlying error.
+func (e *FFmpegError) Unwrap() error {
+	return e.Err
+}
+
+// stderrRingBuffer is an io.Writer backed by a fixed-size circular
+// buffer: it retains only the last limit bytes written to it.
+type stderrRingBuffer struct {
+	mu    sync.Mutex
+	buf   []byte
+	limit int
+	pos   int
+	full  bool
+}
+
+func newStderrRingBuffer(limit int) *stderrRingBuffer {
+	return &stderrRingBuffer{buf: make([]byte, limit), limit: limit}
+}
+
+func (r *stderrRingBuffer) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	n := len(p)
+	if len(p) > r.limit {
+		// Only the tail can survive anyway; skip straight to it.
+		p = p[len(p)-r.limit:]
+		r.pos = 0
+		r.full = true
+	}
+
+	for len(p) > 0 {
+		c := copy(r.buf[r.pos:], p)
+		p = p[c:]
+		r.pos += c
+		if r.pos == r.limit {
+			r.pos = 0
+			r.full = true
+		}
+	}
+	return n, nil
+}
+
+func (r *stderrRingBuffer) String() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.full {
+		return string(r.buf[:r.pos])
+	}
+
+	out := make([]byte, r.limit)
+	n := copy(out, r.buf[r.pos:])
+	copy(out[n:], r.buf[:r.pos])
+	return string(out)
+}
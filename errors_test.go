@@ -0,0 +1,42 @@
+package ffmpeg
+
+import "testing"
+
+func TestStderrRingBufferRetainsTail(t *testing.T) {
+	r := newStderrRingBuffer(4)
+
+	r.Write([]byte("ab"))
+	if got := r.String(); got != "ab" {
+		t.Fatalf("after partial write: got %q, want %q", got, "ab")
+	}
+
+	r.Write([]byte("cdef"))
+	if got := r.String(); got != "cdef" {
+		t.Fatalf("after first wrap: got %q, want %q", got, "cdef")
+	}
+
+	r.Write([]byte("gh"))
+	if got := r.String(); got != "efgh" {
+		t.Fatalf("after wrap: got %q, want %q", got, "efgh")
+	}
+}
+
+func TestStderrRingBufferSingleWriteLargerThanLimit(t *testing.T) {
+	r := newStderrRingBuffer(4)
+
+	r.Write([]byte("abcdefgh"))
+	if got := r.String(); got != "efgh" {
+		t.Fatalf("got %q, want %q", got, "efgh")
+	}
+}
+
+func TestStderrRingBufferManySmallWrites(t *testing.T) {
+	r := newStderrRingBuffer(3)
+
+	for _, b := range []byte("abcdefg") {
+		r.Write([]byte{b})
+	}
+	if got := r.String(); got != "efg" {
+		t.Fatalf("got %q, want %q", got, "efg")
+	}
+}
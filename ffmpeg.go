@@ -1,11 +1,9 @@
 package ffmpeg
 
 import (
-	"bytes"
 	"context"
 	"errors"
 	"fmt"
-	"os/exec"
 	"path/filepath"
 	"strings"
 	"time"
@@ -19,9 +17,19 @@ var (
 	mp4ConvertOption      *MP4ConvertOption
 )
 
-// SetFFMPEGBinPath sets the global path to find and execute the ffmpeg program
-func SetFFMPEGBinPath(newBinPath string) {
+// SetFFMPEGBinPath sets the global path to find and execute the ffmpeg
+// program. It triggers re-discovery so a bad path is surfaced immediately
+// rather than on first use; the previous path is kept if discovery fails.
+func SetFFMPEGBinPath(newBinPath string) error {
+	previous := ffmpegBinPath
 	ffmpegBinPath = newBinPath
+	ffmpegVersion = ""
+
+	if err := DiscoverBinaries(); err != nil {
+		ffmpegBinPath = previous
+		return err
+	}
+	return nil
 }
 
 // ExtractingImagesOption extracting images option
@@ -30,6 +38,10 @@ type ExtractingImagesOption struct {
 	OutputWidth  *uint
 	OutputHeight *uint
 	FilePath     string
+
+	// ProgressFunc, if set, is invoked once per progress block reported by
+	// ffmpeg while the command runs.
+	ProgressFunc ProgressFunc
 }
 
 // DefaultExtractingImagesOption set default extract image option
@@ -58,78 +70,33 @@ func ExtractingImages(timeout time.Duration) error {
 // ExtractingImagesContext is used for retrieve the first frame of given media file using ffmpeg.
 // It takes a context to allow killing the ffmpeg process if it takes too long or in case of shutdown.
 // // ffmpeg -i intro.mp4 -r 0.5 -s 640x320 -f image2 intro-%03d.jpeg
+//
+// Deprecated: extractingImageOption is a package-level global, so two
+// goroutines extracting frames from different files will race on it. Use
+// (*Client).ExtractingImagesContext with an explicit
+// *ExtractingImagesOption instead.
 func ExtractingImagesContext(ctx context.Context) error {
 	if extractingImageOption == nil {
 		return fmt.Errorf("option not set")
 	}
-	outputFileFormat := resolveOutputFrameFileFormat(extractingImageOption.FilePath)
-	resize := extractingImageOption.OutputWidth != nil && extractingImageOption.OutputHeight != nil
-
-	var args []string
-	if resize {
-		args = []string{
-			"-i", extractingImageOption.FilePath,
-			"-r", extractingImageOption.FrameRate,
-			"-s", fmt.Sprintf("%dx%d", *extractingImageOption.OutputWidth, *extractingImageOption.OutputHeight),
-			"-f", "image2",
-			outputFileFormat,
-		}
-	} else {
-		args = []string{
-			"-i", extractingImageOption.FilePath,
-			"-r", extractingImageOption.FrameRate,
-			"-f", "image2",
-			outputFileFormat,
-		}
-	}
-
-	cmd := exec.Command(
-		ffmpegBinPath,
-		args...,
-	)
-
-	var outputBuf bytes.Buffer
-	cmd.Stdout = &outputBuf
-
-	err := cmd.Start()
-	if err == exec.ErrNotFound {
-		return ErrFFMPEGNotFound
-	} else if err != nil {
-		return err
-	}
-
-	done := make(chan error, 1)
-	go func() {
-		done <- cmd.Wait()
-	}()
-
-	select {
-	case <-ctx.Done():
-		err = cmd.Process.Kill()
-		if err == nil {
-			return ErrTimeout
-		}
-		return err
-	case err = <-done:
-		if err != nil {
-			return err
-		}
-	}
-
-	return nil
+	return NewClient().ExtractingImagesContext(ctx, extractingImageOption)
 }
 
 // MP4ConvertOption convert other formats to mp4 option
 type MP4ConvertOption struct {
 	Overwrite bool
-	filePath  string
+	FilePath  string
+
+	// ProgressFunc, if set, is invoked once per progress block reported by
+	// ffmpeg while the command runs.
+	ProgressFunc ProgressFunc
 }
 
 // DefaultMP4ConvertOption set default converter option
 func DefaultMP4ConvertOption(filePath string) {
 	mp4ConvertOption = &MP4ConvertOption{
 		Overwrite: true,
-		filePath:  filePath,
+		FilePath:  filePath,
 	}
 }
 
@@ -151,55 +118,16 @@ func ConvertToMP4(timeout time.Duration) error {
 // ConvertToMP4Context is used to convert a video with other format to mp4 using ffmpeg.
 // It takes a context to allow killing the ffmpeg process if it takes too long or in case of shutdown.
 // // ffmpeg -i target.mov desc.mp4 <<-y>>
+//
+// Deprecated: mp4ConvertOption is a package-level global, so two
+// goroutines converting different files will race on it. Use
+// (*Client).ConvertToMP4Context with an explicit *MP4ConvertOption
+// instead.
 func ConvertToMP4Context(ctx context.Context) (err error) {
 	if mp4ConvertOption == nil {
 		return fmt.Errorf("option not set")
 	}
-	outputFileFormat := resolveOutputMP4FileFormat(mp4ConvertOption.filePath)
-
-	args := []string{
-		"-i", mp4ConvertOption.filePath,
-		outputFileFormat,
-	}
-
-	if mp4ConvertOption.Overwrite {
-		args = append(args, "-y")
-	}
-
-	cmd := exec.Command(
-		ffmpegBinPath,
-		args...,
-	)
-
-	var outputBuf bytes.Buffer
-	cmd.Stdout = &outputBuf
-
-	err = cmd.Start()
-	if err == exec.ErrNotFound {
-		return ErrFFMPEGNotFound
-	} else if err != nil {
-		return err
-	}
-
-	done := make(chan error, 1)
-	go func() {
-		done <- cmd.Wait()
-	}()
-
-	select {
-	case <-ctx.Done():
-		err = cmd.Process.Kill()
-		if err == nil {
-			return ErrTimeout
-		}
-		return err
-	case err = <-done:
-		if err != nil {
-			return err
-		}
-	}
-
-	return nil
+	return NewClient().ConvertToMP4Context(ctx, mp4ConvertOption)
 }
 
 func resolveOutputFrameFileFormat(filePath string) string {
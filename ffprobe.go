@@ -19,9 +19,19 @@ var (
 	ffprobeBinPath = "ffprobe"
 )
 
-// SetFFProbeBinPath sets the global path to find and execute the ffprobe program
-func SetFFProbeBinPath(newBinPath string) {
+// SetFFProbeBinPath sets the global path to find and execute the ffprobe
+// program. It triggers re-discovery so a bad path is surfaced immediately
+// rather than on first use; the previous path is kept if discovery fails.
+func SetFFProbeBinPath(newBinPath string) error {
+	previous := ffprobeBinPath
 	ffprobeBinPath = newBinPath
+	ffprobeVersion = ""
+
+	if err := DiscoverBinaries(); err != nil {
+		ffprobeBinPath = previous
+		return err
+	}
+	return nil
 }
 
 // GetProbeData is used for probing the given media file using ffprobe with a set timeout.
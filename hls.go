@@ -0,0 +1,125 @@
+package ffmpeg
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// HLSPlaylistType is the "-hls_playlist_type" value.
+type HLSPlaylistType string
+
+const (
+	// HLSPlaylistVOD produces a playlist with "#EXT-X-ENDLIST", suitable
+	// for on-demand playback of a finished file.
+	HLSPlaylistVOD HLSPlaylistType = "vod"
+	// HLSPlaylistEvent produces a playlist that only ever appends
+	// segments, suitable for an in-progress live event.
+	HLSPlaylistEvent HLSPlaylistType = "event"
+)
+
+// HLSSegmentFormat is the "-hls_segment_type" value.
+type HLSSegmentFormat string
+
+const (
+	// HLSSegmentMPEGTS produces .ts segments, the original/most
+	// compatible HLS segment container.
+	HLSSegmentMPEGTS HLSSegmentFormat = "mpegts"
+	// HLSSegmentFMP4 produces fragmented MP4 segments.
+	HLSSegmentFMP4 HLSSegmentFormat = "fmp4"
+)
+
+// HLSOption configures an HLS (HTTP Live Streaming) segmenter run.
+type HLSOption struct {
+	FilePath string
+
+	// OutputDir is the directory segments and the playlist are written
+	// into. It must already exist.
+	OutputDir string
+	// PlaylistName is the playlist file name, e.g. "index.m3u8".
+	PlaylistName string
+	// BaseURL is prepended to each segment URI in the playlist, useful
+	// when segments are served from a different path/CDN than the
+	// playlist itself.
+	BaseURL string
+
+	// SegmentDuration is the target segment length in seconds.
+	SegmentDuration int
+	PlaylistType    HLSPlaylistType
+	SegmentFormat   HLSSegmentFormat
+	// HLSFlags are passed verbatim as a comma-joined "-hls_flags" value,
+	// e.g. []string{"independent_segments", "single_file"}.
+	HLSFlags []string
+	// KeyInfoFile, if set, points ffmpeg at an AES-128 key info file
+	// ("-hls_key_info_file") to produce an encrypted stream.
+	KeyInfoFile string
+
+	// ProgressFunc, if set, is invoked once per progress block reported
+	// by ffmpeg while the command runs.
+	ProgressFunc ProgressFunc
+}
+
+// ConvertToHLSContext segments opt.FilePath into an HLS playlist plus
+// media segments using ffmpeg, e.g.:
+// // ffmpeg -i in.mp4 -c:v copy -c:a copy -hls_time 6 -hls_playlist_type vod \
+// //   -hls_segment_filename dir/seg-%03d.ts dir/index.m3u8
+// It takes a context to allow killing the ffmpeg process if it takes too
+// long or in case of shutdown.
+func ConvertToHLSContext(ctx context.Context, opt *HLSOption) error {
+	return NewClient().ConvertToHLSContext(ctx, opt)
+}
+
+// ConvertToHLSContext segments opt.FilePath into an HLS playlist plus
+// media segments using ffmpeg, using this Client's binary path and
+// logger.
+func (c *Client) ConvertToHLSContext(ctx context.Context, opt *HLSOption) error {
+	if opt == nil {
+		return fmt.Errorf("option not set")
+	}
+	if opt.OutputDir == "" {
+		return fmt.Errorf("output dir not set")
+	}
+
+	playlistName := opt.PlaylistName
+	if playlistName == "" {
+		playlistName = "index.m3u8"
+	}
+
+	segmentExt := "ts"
+	if opt.SegmentFormat == HLSSegmentFMP4 {
+		segmentExt = "m4s"
+	}
+
+	args := []string{
+		"-i", opt.FilePath,
+		"-c:v", "copy",
+		"-c:a", "copy",
+	}
+
+	if opt.SegmentDuration > 0 {
+		args = append(args, "-hls_time", fmt.Sprintf("%d", opt.SegmentDuration))
+	}
+	if opt.PlaylistType != "" {
+		args = append(args, "-hls_playlist_type", string(opt.PlaylistType))
+	}
+	if opt.SegmentFormat != "" {
+		args = append(args, "-hls_segment_type", string(opt.SegmentFormat))
+	}
+	if len(opt.HLSFlags) > 0 {
+		args = append(args, "-hls_flags", strings.Join(opt.HLSFlags, ","))
+	}
+	if opt.KeyInfoFile != "" {
+		args = append(args, "-hls_key_info_file", opt.KeyInfoFile)
+	}
+	if opt.BaseURL != "" {
+		args = append(args, "-hls_base_url", opt.BaseURL)
+	}
+
+	args = append(args,
+		"-hls_segment_filename", filepath.Join(opt.OutputDir, "seg-%03d."+segmentExt),
+		filepath.Join(opt.OutputDir, playlistName),
+	)
+
+	return c.Run(ctx, args, opt.FilePath, opt.ProgressFunc)
+}
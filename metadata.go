@@ -0,0 +1,126 @@
+package ffmpeg
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// ClearMetadata strips all container/stream metadata from filePath in
+// place: it writes the stripped output to a temp file alongside filePath,
+// then atomically renames it back over the original, preserving the
+// original's permissions and modification time. It refuses to run if
+// ffprobe reports any encrypted stream on the input.
+func ClearMetadata(ctx context.Context, filePath string) error {
+	data, err := GetProbeDataContext(ctx, filePath)
+	if err != nil {
+		return err
+	}
+	if hasEncryptedStreams(data) {
+		return fmt.Errorf("%s: refusing to clear metadata on an encrypted stream", filePath)
+	}
+
+	info, err := os.Stat(filePath)
+	if err != nil {
+		return err
+	}
+
+	tmpFile := filePath + ".tmp" + filepath.Ext(filePath)
+	args := []string{
+		"-i", filePath,
+		"-map", "0",
+		"-map_metadata", "-1",
+		"-c", "copy",
+		"-y",
+		tmpFile,
+	}
+
+	if err := NewClient().Run(ctx, args, filePath, nil); err != nil {
+		os.Remove(tmpFile)
+		return err
+	}
+
+	if err := os.Chmod(tmpFile, info.Mode()); err != nil {
+		os.Remove(tmpFile)
+		return err
+	}
+	if err := os.Chtimes(tmpFile, info.ModTime(), info.ModTime()); err != nil {
+		os.Remove(tmpFile)
+		return err
+	}
+
+	return os.Rename(tmpFile, filePath)
+}
+
+// Remux changes filePath's container to outContainer, an ffmpeg muxer
+// name passed via "-f" (e.g. "mp4", "mov"), without re-encoding, using
+// "-c copy". The remuxed file is written
+// alongside the input with its extension replaced by outContainer; ffmpeg
+// always writes to a distinct temp file first and the result is renamed
+// into place afterwards, so a remux back into the input's own container
+// (outFile == filePath) doesn't read and write the same file at once.
+func Remux(ctx context.Context, filePath, outContainer string) error {
+	if outContainer == "" {
+		return fmt.Errorf("output container not set")
+	}
+
+	baseFileName := filepath.Base(filePath)
+	outFile := filepath.Join(
+		filepath.Dir(filePath),
+		trimExt(baseFileName)+"."+outContainer,
+	)
+
+	tmpFile := outFile + ".tmp"
+	args := []string{
+		"-i", filePath,
+		"-c", "copy",
+		"-f", outContainer,
+		"-y",
+		tmpFile,
+	}
+
+	if err := NewClient().Run(ctx, args, filePath, nil); err != nil {
+		os.Remove(tmpFile)
+		return err
+	}
+
+	return os.Rename(tmpFile, outFile)
+}
+
+func trimExt(fileName string) string {
+	ext := filepath.Ext(fileName)
+	return fileName[:len(fileName)-len(ext)]
+}
+
+// encryptedCodecTags are ffprobe's "codec_tag_string" values for known
+// encrypted sample entry types: "enca"/"encv" are the generic ISO/IEC
+// 14496-12 encrypted audio/video sample entries (used by CENC/fMP4), and
+// "drms"/"drmi" are Apple's FairPlay-protected audio/video entries.
+var encryptedCodecTags = map[string]bool{
+	"enca": true,
+	"encv": true,
+	"drms": true,
+	"drmi": true,
+}
+
+// hasEncryptedStreams reports whether data describes any stream ffprobe
+// flags as encrypted, via a known encrypted codec_tag_string or an
+// "enc_key_id" stream tag (ffprobe's marker for CENC-encrypted content).
+// An empty/absent codec_name alone is not treated as encrypted, since
+// ordinary data and attachment streams (e.g. embedded fonts, timecode
+// tracks) commonly report no codec_name too.
+func hasEncryptedStreams(data *ProbeData) bool {
+	if data == nil {
+		return false
+	}
+	for _, stream := range data.Streams {
+		if encryptedCodecTags[stream.CodecTag] {
+			return true
+		}
+		if _, ok := stream.Tags["enc_key_id"]; ok {
+			return true
+		}
+	}
+	return false
+}
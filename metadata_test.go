@@ -0,0 +1,59 @@
+package ffmpeg
+
+import "testing"
+
+func TestHasEncryptedStreams(t *testing.T) {
+	tests := []struct {
+		name string
+		data *ProbeData
+		want bool
+	}{
+		{"nil data", nil, false},
+		{"no streams", &ProbeData{}, false},
+		{
+			"ordinary video+audio",
+			&ProbeData{Streams: []StreamInfo{
+				{CodecName: "h264", CodecTag: "avc1"},
+				{CodecName: "aac", CodecTag: "mp4a"},
+			}},
+			false,
+		},
+		{
+			"data/attachment stream with no codec name is not encrypted",
+			&ProbeData{Streams: []StreamInfo{
+				{CodecName: "h264", CodecTag: "avc1"},
+				{CodecName: "", CodecType: "attachment", CodecTag: "[0][0][0][0]"},
+			}},
+			false,
+		},
+		{
+			"FairPlay encrypted audio",
+			&ProbeData{Streams: []StreamInfo{
+				{CodecName: "aac", CodecTag: "drms"},
+			}},
+			true,
+		},
+		{
+			"CENC encrypted video via codec tag",
+			&ProbeData{Streams: []StreamInfo{
+				{CodecName: "h264", CodecTag: "encv"},
+			}},
+			true,
+		},
+		{
+			"CENC encrypted stream flagged via enc_key_id tag",
+			&ProbeData{Streams: []StreamInfo{
+				{CodecName: "h264", CodecTag: "avc1", Tags: map[string]string{"enc_key_id": "abc123"}},
+			}},
+			true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := hasEncryptedStreams(tt.data); got != tt.want {
+				t.Errorf("hasEncryptedStreams() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package ffmpeg
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// Progress carries a single periodic snapshot of an in-flight ffmpeg run,
+// parsed from ffmpeg's "-progress pipe:2 -nostats" output.
+type Progress struct {
+	FrameNum       int64
+	FPS            float64
+	BitrateKbps    float64
+	TotalSizeBytes int64
+	OutTimeMicros  int64
+	SpeedX         float64
+	// PercentComplete is OutTimeMicros relative to the input's total
+	// duration, as reported by ffprobe, or 0 if unknown.
+	PercentComplete float64
+}
+
+// ProgressFunc is invoked once per progress block emitted by ffmpeg.
+type ProgressFunc func(Progress)
+
+// watchProgress scans r for ffmpeg's "key=value" progress lines and
+// invokes fn once per block. It is a no-op if fn is nil; if tee is
+// non-nil, every raw line is also written to it.
+func watchProgress(r io.Reader, durationSeconds float64, fn ProgressFunc, tee io.Writer) {
+	if fn == nil {
+		return
+	}
+
+	var p Progress
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if tee != nil {
+			tee.Write([]byte(line + "\n"))
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+
+		switch key {
+		case "frame":
+			p.FrameNum, _ = strconv.ParseInt(value, 10, 64)
+		case "fps":
+			p.FPS, _ = strconv.ParseFloat(value, 64)
+		case "bitrate":
+			p.BitrateKbps = parseBitrateKbps(value)
+		case "total_size":
+			p.TotalSizeBytes, _ = strconv.ParseInt(value, 10, 64)
+		case "out_time_us":
+			if us, err := strconv.ParseInt(value, 10, 64); err == nil && us >= 0 {
+				p.OutTimeMicros = us
+				if durationSeconds > 0 {
+					p.PercentComplete = minFloat((float64(us)/1e6/durationSeconds)*100, 100)
+				}
+			}
+		case "speed":
+			p.SpeedX = parseSpeedX(value)
+		case "progress":
+			fn(p)
+			if value == "end" {
+				return
+			}
+			p = Progress{}
+		}
+	}
+}
+
+// parseBitrateKbps parses ffmpeg's "123.4kbits/s" (or "N/A") bitrate field.
+func parseBitrateKbps(value string) float64 {
+	value = strings.TrimSuffix(value, "kbits/s")
+	kbps, _ := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	return kbps
+}
+
+// parseSpeedX parses ffmpeg's "2.5x" (or "N/A") speed field.
+func parseSpeedX(value string) float64 {
+	speed, _ := strconv.ParseFloat(strings.TrimSuffix(value, "x"), 64)
+	return speed
+}
+
+func minFloat(a, b float64) float64 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// attachProgress wires cmd's stderr to a pipe ffmpeg's "-progress pipe:2"
+// output can be read from. It returns nil, nil if fn is nil.
+func attachProgress(cmd *exec.Cmd, fn ProgressFunc) (io.ReadCloser, error) {
+	if fn == nil {
+		return nil, nil
+	}
+	return cmd.StderrPipe()
+}
+
+// probeDurationSeconds fetches the total duration of filePath via ffprobe,
+// returning 0 if it cannot be determined.
+func probeDurationSeconds(ctx context.Context, filePath string) float64 {
+	data, err := GetProbeDataContext(ctx, filePath)
+	if err != nil || data == nil || data.Format == nil {
+		return 0
+	}
+
+	duration, err := strconv.ParseFloat(data.Format.Duration, 64)
+	if err != nil {
+		return 0
+	}
+	return duration
+}
@@ -0,0 +1,110 @@
+package ffmpeg
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseBitrateKbps(t *testing.T) {
+	tests := []struct {
+		value string
+		want  float64
+	}{
+		{"1234.5kbits/s", 1234.5},
+		{"0.0kbits/s", 0},
+		{"N/A", 0},
+		{"", 0},
+	}
+
+	for _, tt := range tests {
+		if got := parseBitrateKbps(tt.value); got != tt.want {
+			t.Errorf("parseBitrateKbps(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestParseSpeedX(t *testing.T) {
+	tests := []struct {
+		value string
+		want  float64
+	}{
+		{"2.5x", 2.5},
+		{"0x", 0},
+		{"N/A", 0},
+		{"", 0},
+	}
+
+	for _, tt := range tests {
+		if got := parseSpeedX(tt.value); got != tt.want {
+			t.Errorf("parseSpeedX(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
+
+const sampleProgressOutput = `frame=100
+fps=25.0
+bitrate=1234.5kbits/s
+total_size=2048
+out_time_us=4000000
+speed=2.0x
+progress=continue
+frame=200
+fps=24.5
+bitrate=N/A
+total_size=4096
+out_time_us=8000000
+speed=N/A
+progress=end
+`
+
+func TestWatchProgressParsesBlocks(t *testing.T) {
+	var got []Progress
+	watchProgress(strings.NewReader(sampleProgressOutput), 10, func(p Progress) {
+		got = append(got, p)
+	}, nil)
+
+	if len(got) != 2 {
+		t.Fatalf("got %d progress blocks, want 2", len(got))
+	}
+
+	first := got[0]
+	if first.FrameNum != 100 || first.FPS != 25.0 || first.BitrateKbps != 1234.5 ||
+		first.TotalSizeBytes != 2048 || first.OutTimeMicros != 4000000 || first.SpeedX != 2.0 {
+		t.Errorf("unexpected first block: %+v", first)
+	}
+	if first.PercentComplete != 40 {
+		t.Errorf("first.PercentComplete = %v, want 40", first.PercentComplete)
+	}
+
+	second := got[1]
+	if second.FrameNum != 200 || second.BitrateKbps != 0 || second.SpeedX != 0 {
+		t.Errorf("unexpected second block: %+v", second)
+	}
+	if second.PercentComplete != 80 {
+		t.Errorf("second.PercentComplete = %v, want 80", second.PercentComplete)
+	}
+}
+
+func TestWatchProgressStopsAtEnd(t *testing.T) {
+	calls := 0
+	watchProgress(strings.NewReader(sampleProgressOutput+"frame=300\nprogress=continue\n"), 0, func(Progress) {
+		calls++
+	}, nil)
+
+	if calls != 2 {
+		t.Errorf("watchProgress invoked fn %d times, want 2 (should stop at progress=end)", calls)
+	}
+}
+
+func TestWatchProgressTeesRawLines(t *testing.T) {
+	var tee strings.Builder
+	watchProgress(strings.NewReader(sampleProgressOutput), 0, func(Progress) {}, &tee)
+
+	if !strings.Contains(tee.String(), "frame=100") {
+		t.Errorf("tee did not capture raw stderr lines: %q", tee.String())
+	}
+}
+
+func TestWatchProgressNilFuncIsNoop(t *testing.T) {
+	watchProgress(strings.NewReader(sampleProgressOutput), 10, nil, nil)
+}
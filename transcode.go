@@ -0,0 +1,134 @@
+package ffmpeg
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os/exec"
+)
+
+// TranscodeOption configures a streaming transcode run. Unlike
+// ExtractingImagesOption/MP4ConvertOption, the result is never written to
+// disk: ffmpeg's output is piped directly back to the caller.
+type TranscodeOption struct {
+	FilePath string
+
+	// Codec is the audio codec passed via "-c:a", e.g. "libmp3lame".
+	Codec string
+	// Container is the output format passed via "-f", e.g. "mp3", "flac".
+	Container string
+	// MaxBitrateKbps sets "-b:a %dk" when positive.
+	MaxBitrateKbps int
+	// OffsetSeconds seeks into the input with "-ss %d" before transcoding.
+	OffsetSeconds int
+
+	// Logger, if set, receives ffmpeg's stderr output line by line so
+	// callers can surface encode warnings/errors without inspecting the
+	// returned ReadCloser.
+	Logger *log.Logger
+
+	// ProgressFunc, if set, is invoked once per progress block reported by
+	// ffmpeg while the command runs. It is mutually exclusive with
+	// Logger: ffmpeg's stderr can only be consumed once, so setting
+	// ProgressFunc takes priority and Logger is ignored.
+	ProgressFunc ProgressFunc
+}
+
+// transcodeReadCloser streams ffmpeg's stdout and waits on the underlying
+// process once the caller is done reading.
+type transcodeReadCloser struct {
+	stdout io.ReadCloser
+	cmd    *exec.Cmd
+	stderr *stderrRingBuffer
+}
+
+func (t *transcodeReadCloser) Read(p []byte) (int, error) {
+	return t.stdout.Read(p)
+}
+
+func (t *transcodeReadCloser) Close() error {
+	t.stdout.Close()
+	if err := t.cmd.Wait(); err != nil {
+		return &FFmpegError{
+			Err:      err,
+			Args:     t.cmd.Args,
+			ExitCode: t.cmd.ProcessState.ExitCode(),
+			Stderr:   t.stderr.String(),
+		}
+	}
+	return nil
+}
+
+// Transcode runs ffmpeg against opts.FilePath and streams the transcoded
+// output over stdout as an io.ReadCloser, instead of writing a file to
+// disk. It takes a context to allow killing the ffmpeg process if it takes
+// too long or in case of shutdown.
+// // ffmpeg -ss 30 -i input.mp4 -c:a libmp3lame -b:a 128k -f mp3 -
+func Transcode(ctx context.Context, opts *TranscodeOption) (io.ReadCloser, error) {
+	if opts == nil {
+		return nil, fmt.Errorf("option not set")
+	}
+	if opts.Container == "" {
+		return nil, fmt.Errorf("container not set")
+	}
+
+	var args []string
+	if opts.OffsetSeconds > 0 {
+		args = append(args, "-ss", fmt.Sprintf("%d", opts.OffsetSeconds))
+	}
+	args = append(args, "-i", opts.FilePath)
+	if opts.Codec != "" {
+		args = append(args, "-c:a", opts.Codec)
+	}
+	if opts.MaxBitrateKbps > 0 {
+		args = append(args, "-b:a", fmt.Sprintf("%dk", opts.MaxBitrateKbps))
+	}
+	if opts.ProgressFunc != nil {
+		args = append(args, "-progress", "pipe:2", "-nostats")
+	}
+	args = append(args, "-f", opts.Container, "-")
+
+	cmd := exec.CommandContext(ctx, ffmpegBinPath, args...)
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cmd.Start(); errors.Is(err, exec.ErrNotFound) {
+		return nil, ErrFFMPEGNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	stderrCapture := newStderrRingBuffer(maxCapturedStderr)
+	if opts.ProgressFunc != nil {
+		go watchProgress(stderr, probeDurationSeconds(ctx, opts.FilePath), opts.ProgressFunc, stderrCapture)
+	} else {
+		go logStderr(stderr, opts.Logger, stderrCapture)
+	}
+
+	return &transcodeReadCloser{stdout: stdout, cmd: cmd, stderr: stderrCapture}, nil
+}
+
+// logStderr copies ffmpeg's stderr to logger and tee, one line at a time,
+// so streaming callers can still observe encode warnings/errors. logger
+// may be nil.
+func logStderr(stderr io.Reader, logger *log.Logger, tee io.Writer) {
+	scanner := bufio.NewScanner(stderr)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if logger != nil {
+			logger.Println(line)
+		}
+		tee.Write([]byte(line + "\n"))
+	}
+}